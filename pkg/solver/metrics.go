@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// defaultMetricsListenAddress is used when METRICS_LISTEN_ADDRESS is unset.
+const defaultMetricsListenAddress = ":9092"
+
+// apiRequestsTotal counts NS1 API calls made by the webhook, by operation
+// (e.g. "records.get") and result ("success", "retry", "error"), so
+// operators can alert on sustained failures or rate-limiting.
+var apiRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ns1_webhook_api_requests_total",
+		Help: "Total NS1 API requests made by the webhook, by operation and result.",
+	},
+	[]string{"op", "result"},
+)
+
+// apiRequestDuration tracks the latency of NS1 API calls, by operation.
+var apiRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "ns1_webhook_api_request_duration_seconds",
+		Help:    "Latency of NS1 API requests made by the webhook, by operation.",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"op"},
+)
+
+func init() {
+	prometheus.MustRegister(apiRequestsTotal, apiRequestDuration)
+}
+
+// startMetricsServer starts a best-effort HTTP listener exposing /metrics,
+// so operators can scrape ns1_webhook_api_requests_total and alert on
+// sustained NS1 errors or rate-limiting. The listen address is taken from
+// METRICS_LISTEN_ADDRESS, defaulting to defaultMetricsListenAddress. The
+// server is shut down when stopCh is closed.
+func startMetricsServer(stopCh <-chan struct{}) {
+	addr := os.Getenv("METRICS_LISTEN_ADDRESS")
+	if addr == "" {
+		addr = defaultMetricsListenAddress
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("ns1-webhook: metrics server stopped: %v", err)
+		}
+	}()
+
+	go func() {
+		<-stopCh
+		_ = srv.Close()
+	}()
+}
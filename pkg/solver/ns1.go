@@ -2,12 +2,15 @@ package solver
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/cert-manager/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
 	cmMetaV1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	"github.com/cert-manager/cert-manager/pkg/issuer/acme/dns/util"
+	miekgdns "github.com/miekg/dns"
 	ns1Rest "gopkg.in/ns1/ns1-go.v2/rest"
 	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
 	apiExtensionsV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -15,7 +18,23 @@ import (
 	"k8s.io/client-go/kubernetes"
 	k8sRest "k8s.io/client-go/rest"
 	"net/http"
+	"reflect"
 	"strings"
+	"sync"
+)
+
+// CNAMEStrategy controls how the webhook locates the zone/record to write
+// the challenge TXT answer into.
+type CNAMEStrategy string
+
+const (
+	// CNAMEStrategyNone resolves the zone directly from ch.ResolvedFQDN.
+	// This is the default.
+	CNAMEStrategyNone CNAMEStrategy = "None"
+	// CNAMEStrategyFollow chases any CNAME in front of ch.ResolvedFQDN and
+	// writes the TXT record against the CNAME target's zone instead, so
+	// validation records can live outside the zone the certificate is for.
+	CNAMEStrategyFollow CNAMEStrategy = "Follow"
 )
 
 // Ns1DNSProviderSolver implements the logic needed to 'present' an ACME
@@ -23,7 +42,47 @@ import (
 // `github.com/cert-manager/cert-manager/pkg/acme/webhook.Solver` interface.
 type Ns1DNSProviderSolver struct {
 	k8sClient *kubernetes.Clientset
-	ns1Client *ns1Rest.Client
+
+	ns1ClientsMu sync.RWMutex
+	ns1Clients   map[string]*ns1ClientCacheEntry
+
+	// recordLocks serializes Present/CleanUp calls against the same NS1
+	// record name within this process, keyed by "domain.zone". NS1 has no
+	// conditional-update primitive, so without this a Get-then-Update (or
+	// Get-then-Create) pair racing another goroutine's could clobber its
+	// answer. This only protects a single webhook replica; across replicas,
+	// presentRecord re-fetches and merges when it loses the create race, but
+	// concurrent Updates of the same record can still overwrite each other.
+	recordLocks sync.Map
+}
+
+// recordsAPI is the subset of (*ns1Rest.Client).Records used by the solver.
+// Narrowing it to a local interface lets presentRecord/cleanupRecord be
+// exercised against an in-memory fake in tests, without a real NS1 endpoint.
+type recordsAPI interface {
+	Get(zone, domain, recordType string) (*dns.Record, *http.Response, error)
+	Create(record *dns.Record) (*http.Response, error)
+	Update(record *dns.Record) (*http.Response, error)
+	Delete(zone, domain, recordType string) (*http.Response, error)
+}
+
+// lockRecord locks the mutex for recordName, creating it on first use, and
+// returns a function that unlocks it.
+func (c *Ns1DNSProviderSolver) lockRecord(recordName string) func() {
+	value, _ := c.recordLocks.LoadOrStore(recordName, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// ns1ClientCacheEntry pins an NS1 client to the Secret ResourceVersion and
+// config it was built from, so a rotated API key or a changed
+// Endpoint/IgnoreSSL forces a rebuild instead of reusing stale credentials.
+type ns1ClientCacheEntry struct {
+	client          *ns1Rest.Client
+	resourceVersion string
+	endpoint        string
+	ignoreSSL       bool
 }
 
 // Ns1DNSProviderConfig is a structure that is used to decode into when
@@ -40,6 +99,45 @@ type ns1DNSProviderConfig struct {
 	APIKeySecretRef cmMetaV1.SecretKeySelector `json:"apiKeySecretRef"`
 	Endpoint        string                     `json:"endpoint"`
 	IgnoreSSL       bool                       `json:"ignoreSSL"`
+
+	// CNAMEStrategy selects how the challenge zone/record is located. See
+	// CNAMEStrategyNone and CNAMEStrategyFollow. Defaults to
+	// CNAMEStrategyNone when unset.
+	CNAMEStrategy CNAMEStrategy `json:"cnameStrategy,omitempty"`
+
+	// DelegatedZone, when set, skips zone discovery entirely and writes the
+	// challenge TXT record into this NS1 zone, under DelegatedSubdomain (or,
+	// if that's unset, a name derived from ch.ResolvedFQDN). This lets
+	// operators host _acme-challenge records in a dedicated NS1 zone with
+	// tightly scoped API keys, instead of granting the webhook write access
+	// to production zones.
+	DelegatedZone string `json:"delegatedZone,omitempty"`
+	// DelegatedSubdomain is the record name to use under DelegatedZone. Only
+	// consulted when DelegatedZone is set.
+	DelegatedSubdomain string `json:"delegatedSubdomain,omitempty"`
+
+	// MaxRetries caps how many times a failed NS1 API call is retried.
+	// Defaults to defaultMaxRetries when unset.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// InitialBackoff is the delay before the first retry. Defaults to
+	// defaultInitialBackoff when unset.
+	InitialBackoff k8sMetaV1.Duration `json:"initialBackoff,omitempty"`
+	// MaxBackoff caps the exponential backoff between retries. Defaults to
+	// defaultMaxBackoff when unset.
+	MaxBackoff k8sMetaV1.Duration `json:"maxBackoff,omitempty"`
+
+	// Zone, when set, is used as-is instead of discovering it via
+	// util.FindZoneByFqdn. Useful in air-gapped or restricted-egress
+	// clusters where the webhook Pod has no outbound DNS.
+	Zone string `json:"zone,omitempty"`
+	// ZoneMap maps an FQDN suffix (without the trailing dot, e.g.
+	// "example.com") to the zone that should be used for challenges under
+	// it. The longest matching suffix wins. Consulted when Zone is unset.
+	ZoneMap map[string]string `json:"zoneMap,omitempty"`
+	// Nameservers, when set, is used instead of util.RecursiveNameservers
+	// for zone discovery - e.g. to point at internal resolvers or NS1's own
+	// nameservers instead of the system's recursive resolvers.
+	Nameservers []string `json:"nameservers,omitempty"`
 }
 
 // Name is used as the name for this DNS solver when referencing it on the ACME
@@ -59,31 +157,86 @@ func (c *Ns1DNSProviderSolver) Present(ch *v1alpha1.ChallengeRequest) error {
 		return err
 	}
 
-	zone, domain, err := c.parseChallenge(ch)
+	zone, domain, err := c.parseChallenge(ch, cfg)
 	if err != nil {
 		return err
 	}
 
-	if c.ns1Client == nil {
-		if err := c.setNS1Client(ch, cfg); err != nil {
-			return err
-		}
+	ns1Client, err := c.getNS1Client(ch, cfg)
+	if err != nil {
+		return err
 	}
 
-	// Create a TXT Record for domain.zone with answer set to DNS challenge key
-	// Short TTL is fine, as we delete the record after the challenge is solved.
-	record := dns.NewRecord(zone, domain, "TXT")
-	record.TTL = 600
-	record.AddAnswer(dns.NewTXTAnswer(ch.Key))
+	rc := retryConfigFromCfg(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), rc.budget())
+	defer cancel()
 
-	_, err = c.ns1Client.Records.Create(record)
-	if err != nil {
-		if err != ns1Rest.ErrRecordExists {
-			return err
+	recordName := fmt.Sprintf("%s.%s", domain, zone)
+
+	unlock := c.lockRecord(recordName)
+	defer unlock()
+
+	return presentRecord(ctx, ns1Client.Records, rc, zone, recordName, ch.Key)
+}
+
+// presentRecord adds key's TXT answer to zone/recordName, creating the
+// record if it doesn't exist yet. If another challenge wins the race to
+// create the record between our Get and our Create, the resulting
+// ErrRecordExists is not treated as success: we re-Get the record the
+// winner created and merge our own answer into it, so neither challenge's
+// answer is silently dropped.
+func presentRecord(ctx context.Context, records recordsAPI, rc retryConfig, zone, recordName, key string) error {
+	var record *dns.Record
+	getErr := retryNS1(ctx, "records.get", rc, func() (*http.Response, error) {
+		r, resp, e := records.Get(zone, recordName, "TXT")
+		record = r
+		return resp, e
+	})
+
+	if getErr != nil {
+		if getErr != ns1Rest.ErrRecordMissing {
+			return getErr
+		}
+
+		// No existing record: create one with just our answer. Short TTL is
+		// fine, as we delete the record after the challenge is solved.
+		record = dns.NewRecord(zone, recordName, "TXT")
+		record.TTL = 600
+		record.AddAnswer(dns.NewTXTAnswer(key))
+
+		createErr := retryNS1(ctx, "records.create", rc, func() (*http.Response, error) {
+			return records.Create(record)
+		})
+		if createErr == nil {
+			return nil
+		}
+		if createErr != ns1Rest.ErrRecordExists {
+			return createErr
+		}
+
+		// Lost the create race to a concurrent challenge: fetch what it
+		// created and merge our answer into it below, instead of assuming
+		// ours made it onto the record.
+		getErr = retryNS1(ctx, "records.get", rc, func() (*http.Response, error) {
+			r, resp, e := records.Get(zone, recordName, "TXT")
+			record = r
+			return resp, e
+		})
+		if getErr != nil {
+			return getErr
 		}
 	}
 
-	return nil
+	// Record already exists: add our answer alongside any others (e.g. from
+	// a concurrent wildcard + apex validation) instead of clobbering them.
+	if hasTXTAnswer(record, key) {
+		return nil
+	}
+	record.AddAnswer(dns.NewTXTAnswer(key))
+
+	return retryNS1(ctx, "records.update", rc, func() (*http.Response, error) {
+		return records.Update(record)
+	})
 }
 
 // CleanUp should delete the relevant TXT record from the DNS provider console.
@@ -98,25 +251,82 @@ func (c *Ns1DNSProviderSolver) CleanUp(ch *v1alpha1.ChallengeRequest) error {
 		return err
 	}
 
-	zone, domain, err := c.parseChallenge(ch)
+	zone, domain, err := c.parseChallenge(ch, cfg)
+	if err != nil {
+		return err
+	}
+
+	ns1Client, err := c.getNS1Client(ch, cfg)
 	if err != nil {
 		return err
 	}
 
-	if c.ns1Client == nil {
-		if err := c.setNS1Client(ch, cfg); err != nil {
-			return err
+	rc := retryConfigFromCfg(cfg)
+	ctx, cancel := context.WithTimeout(context.Background(), rc.budget())
+	defer cancel()
+
+	recordName := fmt.Sprintf("%s.%s", domain, zone)
+
+	unlock := c.lockRecord(recordName)
+	defer unlock()
+
+	return cleanupRecord(ctx, ns1Client.Records, rc, zone, recordName, ch.Key)
+}
+
+// cleanupRecord removes only key's TXT answer from zone/recordName,
+// deleting the record outright when it was the last answer left.
+func cleanupRecord(ctx context.Context, records recordsAPI, rc retryConfig, zone, recordName, key string) error {
+	var record *dns.Record
+	getErr := retryNS1(ctx, "records.get", rc, func() (*http.Response, error) {
+		r, resp, e := records.Get(zone, recordName, "TXT")
+		record = r
+		return resp, e
+	})
+	if getErr != nil {
+		if getErr == ns1Rest.ErrRecordMissing {
+			return nil
 		}
+		return getErr
 	}
 
-	// Delete the TXT Record we created in Present
-	if _, err = c.ns1Client.Records.Delete(
-		zone, fmt.Sprintf("%s.%s", domain, zone), "TXT",
-	); err != nil {
-		return err
+	remaining := record.Answers[:0]
+	for _, answer := range record.Answers {
+		if !txtAnswerEqual(answer, key) {
+			remaining = append(remaining, answer)
+		}
 	}
 
-	return nil
+	if len(remaining) == 0 {
+		deleteErr := retryNS1(ctx, "records.delete", rc, func() (*http.Response, error) {
+			return records.Delete(zone, recordName, "TXT")
+		})
+		if deleteErr != nil && deleteErr != ns1Rest.ErrRecordMissing {
+			return deleteErr
+		}
+		return nil
+	}
+
+	record.Answers = remaining
+	return retryNS1(ctx, "records.update", rc, func() (*http.Response, error) {
+		return records.Update(record)
+	})
+}
+
+// hasTXTAnswer reports whether record already carries an answer for key.
+func hasTXTAnswer(record *dns.Record, key string) bool {
+	for _, answer := range record.Answers {
+		if txtAnswerEqual(answer, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// txtAnswerEqual reports whether answer is the TXT answer produced by key,
+// so CleanUp can remove only the record with the matching `key` value, as
+// required by the Solver interface.
+func txtAnswerEqual(answer *dns.Answer, key string) bool {
+	return reflect.DeepEqual(answer.Rdata, dns.NewTXTAnswer(key).Rdata)
 }
 
 // Initialize will be called when the webhook first starts.
@@ -128,12 +338,15 @@ func (c *Ns1DNSProviderSolver) CleanUp(ch *v1alpha1.ChallengeRequest) error {
 // provider accounts.
 // The stopCh can be used to handle early termination of the webhook, in cases
 // where a SIGTERM or similar signal is sent to the webhook process.
-func (c *Ns1DNSProviderSolver) Initialize(kubeClientConfig *k8sRest.Config, _ <-chan struct{}) error {
+func (c *Ns1DNSProviderSolver) Initialize(kubeClientConfig *k8sRest.Config, stopCh <-chan struct{}) error {
 	cl, err := kubernetes.NewForConfig(kubeClientConfig)
 	if err != nil {
 		return err
 	}
 	c.k8sClient = cl
+
+	startMetricsServer(stopCh)
+
 	return nil
 }
 
@@ -152,16 +365,21 @@ func loadConfig(cfgJSON *apiExtensionsV1.JSON) (ns1DNSProviderConfig, error) {
 	return cfg, nil
 }
 
-func (c *Ns1DNSProviderSolver) setNS1Client(ch *v1alpha1.ChallengeRequest, cfg ns1DNSProviderConfig) error {
+// getNS1Client returns the cached NS1 client for this issuer's credentials,
+// rebuilding it if the referenced Secret has changed (or hasn't been seen
+// before). This keeps multiple Issuer/ClusterIssuer resources - each
+// potentially pointing at a different APIKeySecretRef and/or Endpoint - from
+// clobbering one another's credentials.
+func (c *Ns1DNSProviderSolver) getNS1Client(ch *v1alpha1.ChallengeRequest, cfg ns1DNSProviderConfig) (*ns1Rest.Client, error) {
 	ref := cfg.APIKeySecretRef
 	if ref.Name == "" {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"secret for NS1 apiKey not found in '%s'",
 			ch.ResourceNamespace,
 		)
 	}
 	if ref.Key == "" {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"no 'key' set in secret '%s/%s'",
 			ch.ResourceNamespace,
 			ref.Name,
@@ -172,11 +390,22 @@ func (c *Ns1DNSProviderSolver) setNS1Client(ch *v1alpha1.ChallengeRequest, cfg n
 		context.Background(), ref.Name, k8sMetaV1.GetOptions{},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	cacheKey := ns1ClientCacheKey(ch.ResourceNamespace, ref.Name)
+
+	c.ns1ClientsMu.RLock()
+	entry, ok := c.ns1Clients[cacheKey]
+	c.ns1ClientsMu.RUnlock()
+	if ok && entry.resourceVersion == secret.ResourceVersion &&
+		entry.endpoint == cfg.Endpoint && entry.ignoreSSL == cfg.IgnoreSSL {
+		return entry.client, nil
+	}
+
 	apiKeyBytes, ok := secret.Data[ref.Key]
 	if !ok {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"no key '%s' in secret '%s/%s'",
 			ref.Key,
 			ch.ResourceNamespace,
@@ -192,32 +421,195 @@ func (c *Ns1DNSProviderSolver) setNS1Client(ch *v1alpha1.ChallengeRequest, cfg n
 		}
 		httpClient.Transport = tr
 	}
-	c.ns1Client = ns1Rest.NewClient(
+	client := ns1Rest.NewClient(
 		httpClient,
 		ns1Rest.SetAPIKey(apiKey),
 		ns1Rest.SetEndpoint(cfg.Endpoint),
 	)
 
-	return nil
+	c.ns1ClientsMu.Lock()
+	if c.ns1Clients == nil {
+		c.ns1Clients = make(map[string]*ns1ClientCacheEntry)
+	}
+	c.ns1Clients[cacheKey] = &ns1ClientCacheEntry{
+		client:          client,
+		resourceVersion: secret.ResourceVersion,
+		endpoint:        cfg.Endpoint,
+		ignoreSSL:       cfg.IgnoreSSL,
+	}
+	c.ns1ClientsMu.Unlock()
+
+	return client, nil
+}
+
+// ns1ClientCacheKey identifies the single cache slot for an issuer's NS1
+// client, keyed by the Secret it reads credentials from. Keeping one entry
+// per issuer (rather than also keying on Endpoint/IgnoreSSL) means a changed
+// ResourceVersion or config always replaces the existing entry in place
+// instead of leaving it behind as an orphan.
+func ns1ClientCacheKey(namespace, secretName string) string {
+	return fmt.Sprintf("%s/%s", namespace, secretName)
 }
 
 // Get the zone and domain we are setting from the challenge request
-func (c *Ns1DNSProviderSolver) parseChallenge(ch *v1alpha1.ChallengeRequest) (
+func (c *Ns1DNSProviderSolver) parseChallenge(ch *v1alpha1.ChallengeRequest, cfg ns1DNSProviderConfig) (
 	zone string, domain string, err error,
 ) {
+	if cfg.DelegatedZone != "" {
+		zone = util.UnFqdn(cfg.DelegatedZone)
+		domain = cfg.DelegatedSubdomain
+		if domain == "" {
+			domain = delegatedRecordName(ch.ResolvedFQDN)
+		}
+		return zone, domain, nil
+	}
+
+	fqdn := ch.ResolvedFQDN
+	resolvedZone := ch.ResolvedZone
 
-	if zone, err = util.FindZoneByFqdn(
-		ch.ResolvedFQDN, util.RecursiveNameservers,
-	); err != nil {
-		return "", "", err
+	nameservers := util.RecursiveNameservers
+	if len(cfg.Nameservers) > 0 {
+		nameservers = cfg.Nameservers
 	}
-	zone = util.UnFqdn(zone)
 
-	if idx := strings.Index(ch.ResolvedFQDN, "."+ch.ResolvedZone); idx != -1 {
-		domain = ch.ResolvedFQDN[:idx]
+	if cfg.CNAMEStrategy == CNAMEStrategyFollow {
+		// Chase the CNAME against the same nameservers used for zone
+		// discovery below, not the system resolver: a freshly-created
+		// challenge CNAME may not be visible there yet, and restricted-
+		// egress clusters may not allow the Pod to reach it at all.
+		target, cnameErr := resolveCNAME(fqdn, nameservers)
+		if cnameErr != nil {
+			return "", "", cnameErr
+		}
+		if target != "" && target != fqdn {
+			fqdn = target
+			// ch.ResolvedZone describes the original FQDN, not the CNAME
+			// target, so it can't be used to split the new name below.
+			resolvedZone = ""
+		}
+	}
+
+	switch {
+	case cfg.Zone != "":
+		zone = util.UnFqdn(cfg.Zone)
+	default:
+		if mapped, ok := matchZoneMap(fqdn, cfg.ZoneMap); ok {
+			zone = util.UnFqdn(mapped)
+			break
+		}
+
+		if zone, err = util.FindZoneByFqdn(fqdn, nameservers); err != nil {
+			return "", "", err
+		}
+		zone = util.UnFqdn(zone)
+	}
+
+	if resolvedZone != "" {
+		if idx := strings.Index(fqdn, "."+resolvedZone); idx != -1 {
+			return zone, fqdn[:idx], nil
+		}
+	}
+	if idx := strings.Index(fqdn, "."+zone); idx != -1 {
+		domain = fqdn[:idx]
 	} else {
-		domain = util.UnFqdn(ch.ResolvedFQDN)
+		domain = util.UnFqdn(fqdn)
 	}
 
 	return zone, domain, nil
 }
+
+// matchZoneMap returns the zone configured for the longest suffix in
+// zoneMap that matches fqdn, e.g. zoneMap["example.com"] matches both
+// "example.com" and "foo.example.com".
+func matchZoneMap(fqdn string, zoneMap map[string]string) (string, bool) {
+	name := util.UnFqdn(fqdn)
+
+	var bestSuffix, bestZone string
+	for suffix, zone := range zoneMap {
+		s := util.UnFqdn(suffix)
+		if name != s && !strings.HasSuffix(name, "."+s) {
+			continue
+		}
+		if len(s) > len(bestSuffix) {
+			bestSuffix, bestZone = s, zone
+		}
+	}
+
+	return bestZone, bestSuffix != ""
+}
+
+// maxCNAMEHops bounds the number of CNAMEs resolveCNAME will chase, so a
+// misconfigured zone with a CNAME loop fails fast instead of spinning.
+const maxCNAMEHops = 10
+
+// resolveCNAME follows any CNAME chain in front of fqdn and returns the
+// canonical name it ultimately points to. It returns "" (with a nil error)
+// when fqdn has no CNAME and already resolves directly.
+//
+// Like util.FindZoneByFqdn, this queries nameservers directly rather than
+// going through the OS resolver: the CNAME may point at a record that was
+// just created and hasn't propagated to the system resolver's cache yet,
+// and some clusters restrict Pod egress to exactly the configured
+// nameservers.
+func resolveCNAME(fqdn string, nameservers []string) (string, error) {
+	client := &miekgdns.Client{}
+	current := fqdn
+
+	for hop := 0; hop < maxCNAMEHops; hop++ {
+		target, found, err := queryCNAME(client, current, nameservers)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			if hop == 0 {
+				return "", nil
+			}
+			return util.UnFqdn(current), nil
+		}
+		current = target
+	}
+
+	return "", fmt.Errorf("resolveCNAME: %s exceeded %d CNAME hops", fqdn, maxCNAMEHops)
+}
+
+// queryCNAME asks the given nameservers, in order, for the CNAME record at
+// fqdn. A nameserver that cannot be reached is skipped; the first one that
+// answers decides the result, and a non-success rcode from it is returned
+// as an error without asking the remaining nameservers.
+func queryCNAME(client *miekgdns.Client, fqdn string, nameservers []string) (target string, found bool, err error) {
+	m := new(miekgdns.Msg)
+	m.SetQuestion(miekgdns.Fqdn(fqdn), miekgdns.TypeCNAME)
+	m.RecursionDesired = true
+
+	var lastErr error
+	for _, ns := range nameservers {
+		resp, _, exchangeErr := client.Exchange(m, ns)
+		if exchangeErr != nil {
+			lastErr = exchangeErr
+			continue
+		}
+		if resp.Rcode != miekgdns.RcodeSuccess {
+			// A SERVFAIL/REFUSED/NXDOMAIN answer isn't "no CNAME here" -
+			// surface it so cert-manager retries instead of silently
+			// falling back to the original FQDN.
+			return "", false, fmt.Errorf("queryCNAME: %s answered %s for %s", ns, miekgdns.RcodeToString[resp.Rcode], fqdn)
+		}
+
+		for _, rr := range resp.Answer {
+			if cname, ok := rr.(*miekgdns.CNAME); ok {
+				return cname.Target, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	return "", false, lastErr
+}
+
+// delegatedRecordName derives a stable, collision-resistant record name for
+// fqdn when no DelegatedSubdomain is configured, since the delegated zone's
+// namespace is unrelated to the certificate's own domain structure.
+func delegatedRecordName(fqdn string) string {
+	sum := sha256.Sum256([]byte(util.UnFqdn(fqdn)))
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,214 @@
+package solver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cert-manager/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	cmMetaV1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sMetaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// observedRequest records what a fake NS1 endpoint saw, so the test below
+// can tell the two issuers' requests apart.
+type observedRequest struct {
+	mu     sync.Mutex
+	apiKey string
+	hits   int
+}
+
+func (o *observedRequest) record(r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.apiKey = r.Header.Get("X-NSONE-Key")
+	o.hits++
+}
+
+func newFakeNS1Server(obs *observedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		obs.record(r)
+
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"record not found"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"zone":"example","domain":"_acme-challenge","type":"TXT"}`))
+	}))
+}
+
+// TestPresent_PerIssuerClientCache fires concurrent Present calls for two
+// issuers with different secrets/endpoints and checks each one lands on its
+// own NS1 endpoint with its own API key, guarding against getNS1Client
+// pinning a single client's credentials across issuers.
+func TestPresent_PerIssuerClientCache(t *testing.T) {
+	var obsA, obsB observedRequest
+
+	srvA := newFakeNS1Server(&obsA)
+	defer srvA.Close()
+	srvB := newFakeNS1Server(&obsB)
+	defer srvB.Close()
+
+	k8sClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: k8sMetaV1.ObjectMeta{Name: "ns1-creds", Namespace: "issuer-a", ResourceVersion: "1"},
+			Data:       map[string][]byte{"apiKey": []byte("key-a")},
+		},
+		&corev1.Secret{
+			ObjectMeta: k8sMetaV1.ObjectMeta{Name: "ns1-creds", Namespace: "issuer-b", ResourceVersion: "1"},
+			Data:       map[string][]byte{"apiKey": []byte("key-b")},
+		},
+	)
+
+	s := &Ns1DNSProviderSolver{k8sClient: k8sClient}
+
+	apiKeyRef := cmMetaV1.SecretKeySelector{
+		LocalObjectReference: cmMetaV1.LocalObjectReference{Name: "ns1-creds"},
+		Key:                  "apiKey",
+	}
+
+	cfgA := ns1DNSProviderConfig{APIKeySecretRef: apiKeyRef, Endpoint: srvA.URL + "/v1/", Zone: "example.com"}
+	cfgB := ns1DNSProviderConfig{APIKeySecretRef: apiKeyRef, Endpoint: srvB.URL + "/v1/", Zone: "example.org"}
+
+	chA := &v1alpha1.ChallengeRequest{
+		ResourceNamespace: "issuer-a",
+		ResolvedFQDN:      "_acme-challenge.example.com.",
+		ResolvedZone:      "example.com.",
+		Key:               "token-a",
+		Config:            marshalConfig(t, cfgA),
+	}
+	chB := &v1alpha1.ChallengeRequest{
+		ResourceNamespace: "issuer-b",
+		ResolvedFQDN:      "_acme-challenge.example.org.",
+		ResolvedZone:      "example.org.",
+		Key:               "token-b",
+		Config:            marshalConfig(t, cfgB),
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() { defer wg.Done(); errs[0] = s.Present(chA) }()
+	go func() { defer wg.Done(); errs[1] = s.Present(chB) }()
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Present() call %d returned error: %v", i, err)
+		}
+	}
+
+	obsA.mu.Lock()
+	obsB.mu.Lock()
+	defer obsA.mu.Unlock()
+	defer obsB.mu.Unlock()
+
+	if obsA.hits == 0 {
+		t.Fatal("issuer-a's NS1 endpoint was never hit")
+	}
+	if obsB.hits == 0 {
+		t.Fatal("issuer-b's NS1 endpoint was never hit")
+	}
+	if obsA.apiKey != "key-a" {
+		t.Errorf("issuer-a's endpoint saw API key %q, want %q", obsA.apiKey, "key-a")
+	}
+	if obsB.apiKey != "key-b" {
+		t.Errorf("issuer-b's endpoint saw API key %q, want %q", obsB.apiKey, "key-b")
+	}
+}
+
+// TestGetNS1Client_RebuildsOnSecretRotation checks that getNS1Client
+// invalidates its cache entry when the referenced Secret's ResourceVersion
+// changes, instead of reusing a stale client built from the old API key.
+func TestGetNS1Client_RebuildsOnSecretRotation(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: k8sMetaV1.ObjectMeta{Name: "ns1-creds", Namespace: "issuer-a", ResourceVersion: "1"},
+		Data:       map[string][]byte{"apiKey": []byte("key-v1")},
+	})
+
+	s := &Ns1DNSProviderSolver{k8sClient: k8sClient}
+	cfg := ns1DNSProviderConfig{
+		APIKeySecretRef: cmMetaV1.SecretKeySelector{
+			LocalObjectReference: cmMetaV1.LocalObjectReference{Name: "ns1-creds"},
+			Key:                  "apiKey",
+		},
+		Endpoint: "https://api.nsone.net/v1/",
+	}
+	ch := &v1alpha1.ChallengeRequest{ResourceNamespace: "issuer-a"}
+
+	first, err := s.getNS1Client(ch, cfg)
+	if err != nil {
+		t.Fatalf("getNS1Client: %v", err)
+	}
+	again, err := s.getNS1Client(ch, cfg)
+	if err != nil {
+		t.Fatalf("getNS1Client: %v", err)
+	}
+	if first != again {
+		t.Error("expected an unchanged Secret to return the cached client")
+	}
+
+	secret, err := k8sClient.CoreV1().Secrets("issuer-a").Get(context.Background(), "ns1-creds", k8sMetaV1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get secret: %v", err)
+	}
+	secret.Data["apiKey"] = []byte("key-v2")
+	secret.ResourceVersion = "2"
+	if _, err := k8sClient.CoreV1().Secrets("issuer-a").Update(context.Background(), secret, k8sMetaV1.UpdateOptions{}); err != nil {
+		t.Fatalf("update secret: %v", err)
+	}
+
+	rotated, err := s.getNS1Client(ch, cfg)
+	if err != nil {
+		t.Fatalf("getNS1Client after rotation: %v", err)
+	}
+	if rotated == first {
+		t.Error("expected a rotated Secret to force a new client")
+	}
+
+	if got := len(s.ns1Clients); got != 1 {
+		t.Errorf("expected the rotated entry to replace the old one in place, got %d cache entries", got)
+	}
+}
+
+// TestGetNS1Client_RebuildsOnEndpointChange checks that getNS1Client rebuilds
+// (and replaces, rather than leaves orphaned) the cache entry for an issuer
+// whose Endpoint setting changes, even though its Secret didn't.
+func TestGetNS1Client_RebuildsOnEndpointChange(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: k8sMetaV1.ObjectMeta{Name: "ns1-creds", Namespace: "issuer-a", ResourceVersion: "1"},
+		Data:       map[string][]byte{"apiKey": []byte("key-v1")},
+	})
+
+	s := &Ns1DNSProviderSolver{k8sClient: k8sClient}
+	apiKeyRef := cmMetaV1.SecretKeySelector{
+		LocalObjectReference: cmMetaV1.LocalObjectReference{Name: "ns1-creds"},
+		Key:                  "apiKey",
+	}
+	ch := &v1alpha1.ChallengeRequest{ResourceNamespace: "issuer-a"}
+
+	first, err := s.getNS1Client(ch, ns1DNSProviderConfig{APIKeySecretRef: apiKeyRef, Endpoint: "https://api.nsone.net/v1/"})
+	if err != nil {
+		t.Fatalf("getNS1Client: %v", err)
+	}
+
+	changed, err := s.getNS1Client(ch, ns1DNSProviderConfig{APIKeySecretRef: apiKeyRef, Endpoint: "https://api.eu.nsone.net/v1/"})
+	if err != nil {
+		t.Fatalf("getNS1Client after endpoint change: %v", err)
+	}
+	if changed == first {
+		t.Error("expected a changed Endpoint to force a new client")
+	}
+
+	if got := len(s.ns1Clients); got != 1 {
+		t.Errorf("expected the changed entry to replace the old one in place, got %d cache entries", got)
+	}
+}
@@ -0,0 +1,101 @@
+package solver
+
+import (
+	"net"
+	"testing"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+// newFakeCNAMEServer starts a local DNS server that answers CNAME queries
+// from records, or rcode for any name not in records. It returns the
+// server's "host:port" address, suitable for resolveCNAME's nameservers.
+func newFakeCNAMEServer(t *testing.T, records map[string]string, rcode int) string {
+	t.Helper()
+
+	mux := miekgdns.NewServeMux()
+	mux.HandleFunc(".", func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		m := new(miekgdns.Msg)
+		m.SetReply(r)
+
+		q := r.Question[0]
+		if target, ok := records[q.Name]; ok {
+			m.Answer = append(m.Answer, &miekgdns.CNAME{
+				Hdr:    miekgdns.RR_Header{Name: q.Name, Rrtype: miekgdns.TypeCNAME, Class: miekgdns.ClassINET, Ttl: 300},
+				Target: target,
+			})
+		} else {
+			m.Rcode = rcode
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+
+	srv := &miekgdns.Server{PacketConn: pc, Handler: mux}
+	go func() {
+		_ = srv.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = srv.Shutdown()
+	})
+
+	return pc.LocalAddr().String()
+}
+
+func TestResolveCNAME_NoCNAMEReturnsEmpty(t *testing.T) {
+	ns := newFakeCNAMEServer(t, map[string]string{}, miekgdns.RcodeSuccess)
+
+	target, err := resolveCNAME("_acme-challenge.example.com.", []string{ns})
+	if err != nil {
+		t.Fatalf("resolveCNAME: %v", err)
+	}
+	if target != "" {
+		t.Errorf("target = %q, want empty", target)
+	}
+}
+
+func TestResolveCNAME_FollowsSingleHop(t *testing.T) {
+	ns := newFakeCNAMEServer(t, map[string]string{
+		"_acme-challenge.example.com.": "_acme-challenge.validation.example.net.",
+	}, miekgdns.RcodeSuccess)
+
+	target, err := resolveCNAME("_acme-challenge.example.com.", []string{ns})
+	if err != nil {
+		t.Fatalf("resolveCNAME: %v", err)
+	}
+	if target != "_acme-challenge.validation.example.net" {
+		t.Errorf("target = %q, want %q", target, "_acme-challenge.validation.example.net")
+	}
+}
+
+func TestResolveCNAME_FollowsChain(t *testing.T) {
+	ns := newFakeCNAMEServer(t, map[string]string{
+		"_acme-challenge.example.com.":               "_acme-challenge.intermediate.example.net.",
+		"_acme-challenge.intermediate.example.net.": "_acme-challenge.validation.example.org.",
+	}, miekgdns.RcodeSuccess)
+
+	target, err := resolveCNAME("_acme-challenge.example.com.", []string{ns})
+	if err != nil {
+		t.Fatalf("resolveCNAME: %v", err)
+	}
+	if target != "_acme-challenge.validation.example.org" {
+		t.Errorf("target = %q, want %q", target, "_acme-challenge.validation.example.org")
+	}
+}
+
+// TestResolveCNAME_ResolverErrorFailsClosed covers the review fix: a
+// SERVFAIL/REFUSED/NXDOMAIN answer must surface as an error, not be treated
+// as "no CNAME here" and silently fall back to the original FQDN.
+func TestResolveCNAME_ResolverErrorFailsClosed(t *testing.T) {
+	ns := newFakeCNAMEServer(t, map[string]string{}, miekgdns.RcodeServerFailure)
+
+	target, err := resolveCNAME("_acme-challenge.example.com.", []string{ns})
+	if err == nil {
+		t.Fatalf("expected an error for a SERVFAIL answer, got target=%q", target)
+	}
+}
@@ -0,0 +1,253 @@
+package solver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	ns1Rest "gopkg.in/ns1/ns1-go.v2/rest"
+	"gopkg.in/ns1/ns1-go.v2/rest/model/dns"
+)
+
+// fakeRecords is an in-memory recordsAPI used to exercise presentRecord and
+// cleanupRecord without a real NS1 endpoint.
+type fakeRecords struct {
+	mu      sync.Mutex
+	records map[string]*dns.Record
+}
+
+func newFakeRecords() *fakeRecords {
+	return &fakeRecords{records: map[string]*dns.Record{}}
+}
+
+func fakeRecordKey(zone, domain, recordType string) string {
+	return zone + "|" + domain + "|" + recordType
+}
+
+func (f *fakeRecords) Get(zone, domain, recordType string) (*dns.Record, *http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.records[fakeRecordKey(zone, domain, recordType)]
+	if !ok {
+		return nil, nil, ns1Rest.ErrRecordMissing
+	}
+	cp := *r
+	cp.Answers = append([]*dns.Answer(nil), r.Answers...)
+	return &cp, nil, nil
+}
+
+func (f *fakeRecords) Create(record *dns.Record) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeRecordKey(record.Zone, record.Domain, record.Type)
+	if _, ok := f.records[key]; ok {
+		return nil, ns1Rest.ErrRecordExists
+	}
+	f.records[key] = record
+	return nil, nil
+}
+
+func (f *fakeRecords) Update(record *dns.Record) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.records[fakeRecordKey(record.Zone, record.Domain, record.Type)] = record
+	return nil, nil
+}
+
+func (f *fakeRecords) Delete(zone, domain, recordType string) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeRecordKey(zone, domain, recordType)
+	if _, ok := f.records[key]; !ok {
+		return nil, ns1Rest.ErrRecordMissing
+	}
+	delete(f.records, key)
+	return nil, nil
+}
+
+func testRetryConfig() retryConfig {
+	return retryConfigFromCfg(ns1DNSProviderConfig{})
+}
+
+// raceRecords simulates a concurrent challenge winning the create race:
+// the first Get reports the record missing, but by the time Create runs
+// another writer has already created it.
+type raceRecords struct {
+	getCalls int
+	created  *dns.Record
+}
+
+func (r *raceRecords) Get(zone, domain, recordType string) (*dns.Record, *http.Response, error) {
+	r.getCalls++
+	if r.created == nil {
+		return nil, nil, ns1Rest.ErrRecordMissing
+	}
+	cp := *r.created
+	cp.Answers = append([]*dns.Answer(nil), r.created.Answers...)
+	return &cp, nil, nil
+}
+
+func (r *raceRecords) Create(record *dns.Record) (*http.Response, error) {
+	if r.created != nil {
+		return nil, ns1Rest.ErrRecordExists
+	}
+	// A concurrent challenge (e.g. the apex cert's validation) created the
+	// record between our Get and our Create.
+	winner := dns.NewRecord(record.Zone, record.Domain, record.Type)
+	winner.TTL = record.TTL
+	winner.AddAnswer(dns.NewTXTAnswer("token-from-concurrent-winner"))
+	r.created = winner
+	return nil, ns1Rest.ErrRecordExists
+}
+
+func (r *raceRecords) Update(record *dns.Record) (*http.Response, error) {
+	r.created = record
+	return nil, nil
+}
+
+func (r *raceRecords) Delete(zone, domain, recordType string) (*http.Response, error) {
+	r.created = nil
+	return nil, nil
+}
+
+// TestPresentRecord_CreateRaceMergesBothAnswers covers the bug called out
+// in review: losing the create race must not silently drop our own answer.
+func TestPresentRecord_CreateRaceMergesBothAnswers(t *testing.T) {
+	records := &raceRecords{}
+	rc := testRetryConfig()
+
+	err := presentRecord(context.Background(), records, rc, "example.com", "_acme-challenge.example.com", "token-a")
+	if err != nil {
+		t.Fatalf("presentRecord: %v", err)
+	}
+
+	if records.created == nil {
+		t.Fatal("expected a record to exist after presentRecord")
+	}
+	if !hasTXTAnswer(records.created, "token-a") {
+		t.Error("our own answer was dropped after losing the create race")
+	}
+	if !hasTXTAnswer(records.created, "token-from-concurrent-winner") {
+		t.Error("the concurrent winner's answer was dropped")
+	}
+}
+
+// TestCleanupRecord_ConcurrentChallengesPreserveOtherAnswer simulates two
+// concurrent challenges (e.g. wildcard + apex) sharing one TXT record and
+// checks that cleaning up one challenge's key leaves the other intact.
+func TestCleanupRecord_ConcurrentChallengesPreserveOtherAnswer(t *testing.T) {
+	tests := []struct {
+		name        string
+		cleanupKey  string
+		wantSurvive string
+		wantGone    string
+	}{
+		{name: "cleanup first key leaves second", cleanupKey: "token-a", wantSurvive: "token-b", wantGone: "token-a"},
+		{name: "cleanup second key leaves first", cleanupKey: "token-b", wantSurvive: "token-a", wantGone: "token-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := newFakeRecords()
+			rc := testRetryConfig()
+			zone, recordName := "example.com", "_acme-challenge.example.com"
+
+			seed := dns.NewRecord(zone, recordName, "TXT")
+			seed.TTL = 600
+			seed.AddAnswer(dns.NewTXTAnswer("token-a"))
+			seed.AddAnswer(dns.NewTXTAnswer("token-b"))
+			if _, err := records.Create(seed); err != nil {
+				t.Fatalf("seed record: %v", err)
+			}
+
+			if err := cleanupRecord(context.Background(), records, rc, zone, recordName, tt.cleanupKey); err != nil {
+				t.Fatalf("cleanupRecord: %v", err)
+			}
+
+			remaining, _, err := records.Get(zone, recordName, "TXT")
+			if err != nil {
+				t.Fatalf("get after cleanup: %v", err)
+			}
+			if !hasTXTAnswer(remaining, tt.wantSurvive) {
+				t.Errorf("expected %q to survive cleanup, it didn't", tt.wantSurvive)
+			}
+			if hasTXTAnswer(remaining, tt.wantGone) {
+				t.Errorf("expected %q to be removed, it wasn't", tt.wantGone)
+			}
+		})
+	}
+}
+
+// TestCleanupRecord_LastAnswerDeletesRecord checks the record is deleted
+// outright once its last TXT answer is removed.
+func TestCleanupRecord_LastAnswerDeletesRecord(t *testing.T) {
+	records := newFakeRecords()
+	rc := testRetryConfig()
+	zone, recordName := "example.com", "_acme-challenge.example.com"
+
+	seed := dns.NewRecord(zone, recordName, "TXT")
+	seed.TTL = 600
+	seed.AddAnswer(dns.NewTXTAnswer("only-token"))
+	if _, err := records.Create(seed); err != nil {
+		t.Fatalf("seed record: %v", err)
+	}
+
+	if err := cleanupRecord(context.Background(), records, rc, zone, recordName, "only-token"); err != nil {
+		t.Fatalf("cleanupRecord: %v", err)
+	}
+
+	if _, _, err := records.Get(zone, recordName, "TXT"); err != ns1Rest.ErrRecordMissing {
+		t.Errorf("expected record to be deleted, got err=%v", err)
+	}
+
+	// CleanUp must tolerate being called again after the record is gone.
+	if err := cleanupRecord(context.Background(), records, rc, zone, recordName, "only-token"); err != nil {
+		t.Errorf("cleanupRecord on an already-missing record should succeed, got %v", err)
+	}
+}
+
+// TestSolver_ConcurrentCleanupsSerializeOnRecordLock exercises lockRecord
+// directly: two concurrent CleanUp-style calls for different keys on the
+// same record must not race each other's Get-then-Update/Delete.
+func TestSolver_ConcurrentCleanupsSerializeOnRecordLock(t *testing.T) {
+	s := &Ns1DNSProviderSolver{}
+	records := newFakeRecords()
+	rc := testRetryConfig()
+	zone, recordName := "example.com", "_acme-challenge.example.com"
+
+	seed := dns.NewRecord(zone, recordName, "TXT")
+	seed.TTL = 600
+	seed.AddAnswer(dns.NewTXTAnswer("token-a"))
+	seed.AddAnswer(dns.NewTXTAnswer("token-b"))
+	if _, err := records.Create(seed); err != nil {
+		t.Fatalf("seed record: %v", err)
+	}
+
+	cleanup := func(key string) error {
+		unlock := s.lockRecord(recordName)
+		defer unlock()
+		return cleanupRecord(context.Background(), records, rc, zone, recordName, key)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() { defer wg.Done(); errs[0] = cleanup("token-a") }()
+	go func() { defer wg.Done(); errs[1] = cleanup("token-b") }()
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("cleanup %d: %v", i, err)
+		}
+	}
+
+	if _, _, err := records.Get(zone, recordName, "TXT"); err != ns1Rest.ErrRecordMissing {
+		t.Errorf("expected both answers removed and the record deleted, got err=%v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package solver
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	miekgdns "github.com/miekg/dns"
+)
+
+// newFakeZoneServer starts a local DNS server that answers an SOA query for
+// zone (and only zone) so util.FindZoneByFqdn's walk-up-the-labels algorithm
+// can be exercised without reaching the real internet. It returns the
+// server's "host:port" address, suitable for cfg.Nameservers.
+func newFakeZoneServer(t *testing.T, zone string) string {
+	t.Helper()
+
+	zone = miekgdns.Fqdn(zone)
+
+	mux := miekgdns.NewServeMux()
+	mux.HandleFunc(".", func(w miekgdns.ResponseWriter, r *miekgdns.Msg) {
+		m := new(miekgdns.Msg)
+		m.SetReply(r)
+
+		q := r.Question[0]
+		if q.Qtype == miekgdns.TypeSOA && strings.EqualFold(q.Name, zone) {
+			m.Answer = append(m.Answer, &miekgdns.SOA{
+				Hdr:     miekgdns.RR_Header{Name: zone, Rrtype: miekgdns.TypeSOA, Class: miekgdns.ClassINET, Ttl: 300},
+				Ns:      "ns1." + zone,
+				Mbox:    "hostmaster." + zone,
+				Serial:  1,
+				Refresh: 3600,
+				Retry:   600,
+				Expire:  86400,
+				Minttl:  300,
+			})
+		} else {
+			m.Rcode = miekgdns.RcodeNameError
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+
+	srv := &miekgdns.Server{PacketConn: pc, Handler: mux}
+	go func() {
+		_ = srv.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = srv.Shutdown()
+	})
+
+	return pc.LocalAddr().String()
+}
+
+// TestParseChallenge_ZonePrecedence covers the precedence order between
+// cfg.Zone, cfg.ZoneMap, and resolver-based discovery against
+// cfg.Nameservers, for apex, subdomain, and wildcard FQDNs.
+func TestParseChallenge_ZonePrecedence(t *testing.T) {
+	ns := newFakeZoneServer(t, "example.com")
+
+	tests := []struct {
+		name       string
+		fqdn       string
+		cfg        ns1DNSProviderConfig
+		wantZone   string
+		wantDomain string
+	}{
+		{
+			name:       "explicit Zone wins over everything, apex",
+			fqdn:       "example.com.",
+			cfg:        ns1DNSProviderConfig{Zone: "example.com", Nameservers: []string{"127.0.0.1:1"}},
+			wantZone:   "example.com",
+			wantDomain: "example.com",
+		},
+		{
+			name: "ZoneMap wins over resolver discovery, subdomain",
+			fqdn: "_acme-challenge.foo.example.com.",
+			cfg: ns1DNSProviderConfig{
+				ZoneMap:     map[string]string{"example.com": "example.com"},
+				Nameservers: []string{"127.0.0.1:1"},
+			},
+			wantZone:   "example.com",
+			wantDomain: "_acme-challenge.foo",
+		},
+		{
+			name:       "resolver discovery, apex",
+			fqdn:       "example.com.",
+			cfg:        ns1DNSProviderConfig{Nameservers: []string{ns}},
+			wantZone:   "example.com",
+			wantDomain: "example.com",
+		},
+		{
+			name:       "resolver discovery, subdomain",
+			fqdn:       "_acme-challenge.example.com.",
+			cfg:        ns1DNSProviderConfig{Nameservers: []string{ns}},
+			wantZone:   "example.com",
+			wantDomain: "_acme-challenge",
+		},
+		{
+			name:       "resolver discovery, wildcard",
+			fqdn:       "_acme-challenge.*.example.com.",
+			cfg:        ns1DNSProviderConfig{Nameservers: []string{ns}},
+			wantZone:   "example.com",
+			wantDomain: "_acme-challenge.*",
+		},
+	}
+
+	s := &Ns1DNSProviderSolver{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &v1alpha1.ChallengeRequest{ResolvedFQDN: tt.fqdn}
+
+			zone, domain, err := s.parseChallenge(ch, tt.cfg)
+			if err != nil {
+				t.Fatalf("parseChallenge: %v", err)
+			}
+			if zone != tt.wantZone {
+				t.Errorf("zone = %q, want %q", zone, tt.wantZone)
+			}
+			if domain != tt.wantDomain {
+				t.Errorf("domain = %q, want %q", domain, tt.wantDomain)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package solver
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gopkg.in/ns1/ns1-go.v2/rest/ratelimit"
+)
+
+const (
+	defaultMaxRetries     = 5
+	defaultInitialBackoff = 250 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// retryConfig controls the backoff applied around NS1 API calls.
+type retryConfig struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// retryConfigFromCfg builds a retryConfig from the solver config, filling in
+// the package defaults for anything left unset.
+func retryConfigFromCfg(cfg ns1DNSProviderConfig) retryConfig {
+	rc := retryConfig{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+	if cfg.MaxRetries > 0 {
+		rc.MaxRetries = cfg.MaxRetries
+	}
+	if cfg.InitialBackoff.Duration > 0 {
+		rc.InitialBackoff = cfg.InitialBackoff.Duration
+	}
+	if cfg.MaxBackoff.Duration > 0 {
+		rc.MaxBackoff = cfg.MaxBackoff.Duration
+	}
+	return rc
+}
+
+// budget returns a total time budget for a single Present/CleanUp call,
+// sized off the configured retry settings - ChallengeRequest carries no
+// deadline of its own, so this is the closest thing we have to one.
+func (rc retryConfig) budget() time.Duration {
+	return time.Duration(rc.MaxRetries+1) * rc.MaxBackoff
+}
+
+// retryNS1 runs fn, retrying on retryable errors with exponential backoff
+// and jitter, honoring any Retry-After/rate-limit hints on the response,
+// until rc.MaxRetries is exhausted or ctx is done. Every attempt is recorded
+// against the ns1_webhook_api_requests_total counter and latency histogram
+// under op.
+func retryNS1(ctx context.Context, op string, rc retryConfig, fn func() (*http.Response, error)) error {
+	backoff := rc.InitialBackoff
+	var lastErr error
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := fn()
+		apiRequestDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+
+		if err == nil {
+			apiRequestsTotal.WithLabelValues(op, "success").Inc()
+			return nil
+		}
+		lastErr = err
+
+		if attempt >= rc.MaxRetries || !isRetryableNS1Error(err, resp) {
+			apiRequestsTotal.WithLabelValues(op, "error").Inc()
+			return err
+		}
+		apiRequestsTotal.WithLabelValues(op, "retry").Inc()
+
+		wait := withJitter(retryDelay(resp, backoff))
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > rc.MaxBackoff {
+			backoff = rc.MaxBackoff
+		}
+	}
+}
+
+// isRetryableNS1Error reports whether err (with the accompanying response,
+// which may be nil) is worth retrying: rate limiting, server errors, or a
+// transport-level failure such as a timeout.
+func isRetryableNS1Error(err error, resp *http.Response) bool {
+	if err == nil {
+		return false
+	}
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) {
+		return true
+	}
+	var urlErr *url.Error
+	return asURLError(err, &urlErr)
+}
+
+func asURLError(err error, target **url.Error) bool {
+	urlErr, ok := err.(*url.Error)
+	if ok {
+		*target = urlErr
+	}
+	return ok
+}
+
+// retryDelay honors NS1's Retry-After and X-Ratelimit-* headers when
+// present, falling back to fallback otherwise. The X-Ratelimit-* headers are
+// parsed via ns1-go's own ratelimit package rather than by hand, so this
+// stays correct if NS1 ever changes their semantics.
+func retryDelay(resp *http.Response, fallback time.Duration) time.Duration {
+	if resp == nil {
+		return fallback
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	if wait := ratelimit.ParseHeaders(resp).WaitTimeRemaining(0); wait > 0 {
+		return wait
+	}
+	return fallback
+}
+
+// withJitter returns a random duration in [d/2, d], so retries from
+// concurrent challenges don't all land on NS1 at once.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	half := int64(d) / 2
+	return time.Duration(half) + time.Duration(rand.Int63n(half+1))
+}
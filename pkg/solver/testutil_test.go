@@ -0,0 +1,21 @@
+package solver
+
+import (
+	"encoding/json"
+	"testing"
+
+	apiExtensionsV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// marshalConfig encodes cfg the same way cert-manager embeds webhook config
+// on a ChallengeRequest, for use in test fixtures.
+func marshalConfig(t *testing.T, cfg ns1DNSProviderConfig) *apiExtensionsV1.JSON {
+	t.Helper()
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+
+	return &apiExtensionsV1.JSON{Raw: raw}
+}